@@ -0,0 +1,90 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Direction records which side of the call an Entry captures.
+type Direction uint8
+
+const (
+	DirectionClientMessage Direction = iota
+	DirectionServerMessage
+	DirectionServerTrailer
+)
+
+// Entry is one recorded event: a header, a request/response message, or the
+// final status of a call.
+type Entry struct {
+	Timestamp time.Time
+	Method    string
+	Peer      string
+	Direction Direction
+	Status    string
+	Payload   []byte
+}
+
+// maxPayloadBytes truncates oversized messages so one pathological request
+// can't blow out the rotation budget.
+const maxPayloadBytes = 16 * 1024
+
+// marshal writes e as a length-prefixed record: a 4-byte big-endian length
+// followed by that many bytes of a simple binary encoding of the fields
+// above. It is not wire-compatible with protobuf, but follows the same
+// length-prefixed framing grpc-go's binarylog sink uses.
+func (e *Entry) marshal() []byte {
+	payload := e.Payload
+	if len(payload) > maxPayloadBytes {
+		payload = payload[:maxPayloadBytes]
+	}
+	buf := make([]byte, 0, 64+len(e.Method)+len(e.Peer)+len(e.Status)+len(payload))
+	buf = appendString(buf, e.Timestamp.Format(time.RFC3339Nano))
+	buf = appendString(buf, e.Method)
+	buf = appendString(buf, e.Peer)
+	buf = append(buf, byte(e.Direction))
+	buf = appendString(buf, e.Status)
+	buf = appendBytes(buf, payload)
+
+	framed := make([]byte, 4+len(buf))
+	binary.BigEndian.PutUint32(framed, uint32(len(buf)))
+	copy(framed[4:], buf)
+	return framed
+}
+
+func appendString(buf []byte, s string) []byte {
+	return appendBytes(buf, []byte(s))
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(b)))
+	buf = append(buf, lenBuf[:]...)
+	return append(buf, b...)
+}
+
+// writeEntry writes e's framed record to w.
+func writeEntry(w io.Writer, e *Entry) (int, error) {
+	b := e.marshal()
+	n, err := w.Write(b)
+	if err != nil {
+		return n, fmt.Errorf("binlog: write entry: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// marshaler is implemented by the request/response messages this package
+// can capture as raw bytes - pkg/proto's message types, in particular.
+type marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// UnaryServerInterceptor records every unary call matching logger's rules:
+// the request header, the request and response messages, and the final
+// status. Register it alongside the other interceptors on the CNI, infra-
+// agent and infra-manager servers. A nil or disabled logger is a no-op.
+func UnaryServerInterceptor(logger *Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if logger == nil || !logger.Enabled(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		peerAddr := "unknown"
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			peerAddr = p.Addr.String()
+		}
+
+		logger.logHeader(info.FullMethod, peerAddr)
+		if msg, ok := req.(marshaler); ok {
+			if b, err := msg.Marshal(); err == nil {
+				logger.logMessage(info.FullMethod, peerAddr, DirectionClientMessage, b)
+			}
+		}
+
+		resp, err := handler(ctx, req)
+
+		if resp != nil {
+			if msg, ok := resp.(marshaler); ok {
+				if b, merr := msg.Marshal(); merr == nil {
+					logger.logMessage(info.FullMethod, peerAddr, DirectionServerMessage, b)
+				}
+			}
+		}
+		logger.logStatus(info.FullMethod, peerAddr, status.Convert(err).String())
+
+		return resp, err
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Logger decides, per rule, whether and what to record for a gRPC call and
+// hands the result to a Sink.
+type Logger struct {
+	rules []rule
+	sink  Sink
+	log   *log.Entry
+}
+
+// NewLogger builds a Logger from a rule string, e.g. "*=h;CniService/Add=hm",
+// writing matched entries to sink. An empty ruleConfig disables logging
+// entirely: every call becomes a no-op.
+func NewLogger(ruleConfig string, sink Sink) (*Logger, error) {
+	rules, err := parseRules(ruleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("binlog: cannot parse rules: %w", err)
+	}
+	return &Logger{
+		rules: rules,
+		sink:  sink,
+		log:   log.WithField("pkg", "binlog"),
+	}, nil
+}
+
+// Enabled reports whether any rule matches fullMethod.
+func (l *Logger) Enabled(fullMethod string) bool {
+	if l == nil {
+		return false
+	}
+	_, ok := lookup(l.rules, fullMethod)
+	return ok
+}
+
+func (l *Logger) logHeader(fullMethod, peer string) {
+	flags, ok := lookup(l.rules, fullMethod)
+	if !ok || flags&flagHeader == 0 {
+		return
+	}
+	l.write(&Entry{
+		Timestamp: time.Now(),
+		Method:    fullMethod,
+		Peer:      peer,
+		Direction: DirectionClientMessage,
+	})
+}
+
+func (l *Logger) logMessage(fullMethod, peer string, dir Direction, payload []byte) {
+	flags, ok := lookup(l.rules, fullMethod)
+	if !ok || flags&flagMessage == 0 {
+		return
+	}
+	l.write(&Entry{
+		Timestamp: time.Now(),
+		Method:    fullMethod,
+		Peer:      peer,
+		Direction: dir,
+		Payload:   payload,
+	})
+}
+
+func (l *Logger) logStatus(fullMethod, peer, status string) {
+	if _, ok := lookup(l.rules, fullMethod); !ok {
+		return
+	}
+	l.write(&Entry{
+		Timestamp: time.Now(),
+		Method:    fullMethod,
+		Peer:      peer,
+		Direction: DirectionServerTrailer,
+		Status:    status,
+	})
+}
+
+func (l *Logger) write(e *Entry) {
+	if err := l.sink.Write(e); err != nil {
+		l.log.WithError(err).Warn("cannot write binlog entry")
+	}
+}
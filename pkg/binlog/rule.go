@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package binlog records CNI gRPC requests and responses to a rotating file
+// so operators have a post-mortem trail for pod networking events that would
+// otherwise disappear with the pod. It is modeled on the selective
+// per-service/per-method filtering used by grpc-go's binarylog.
+package binlog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flag controls what part of a call a rule captures.
+type flag uint8
+
+const (
+	flagHeader flag = 1 << iota
+	flagMessage
+)
+
+// rule matches a fully qualified gRPC method, e.g. "CniService/Add", or the
+// wildcard "*" for every method, and records what to capture for matches.
+type rule struct {
+	full  string // "*" or "Service/Method"
+	flags flag
+}
+
+func (r rule) matches(fullMethod string) bool {
+	return r.full == "*" || r.full == strings.TrimPrefix(fullMethod, "/")
+}
+
+// parseRules parses a rule string such as "*=h;CniService/Add=hm" into an
+// ordered list of rules. Later rules take precedence over earlier ones for
+// the same method, mirroring grpc-go's binarylog rule semantics.
+func parseRules(config string) ([]rule, error) {
+	config = strings.TrimSpace(config)
+	if config == "" {
+		return nil, nil
+	}
+	var rules []rule
+	for _, part := range strings.Split(config, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.LastIndex(part, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("binlog: invalid rule %q: missing '='", part)
+		}
+		full, flagStr := part[:eq], part[eq+1:]
+		f, err := parseFlags(flagStr)
+		if err != nil {
+			return nil, fmt.Errorf("binlog: invalid rule %q: %w", part, err)
+		}
+		rules = append(rules, rule{full: full, flags: f})
+	}
+	return rules, nil
+}
+
+func parseFlags(s string) (flag, error) {
+	if s == "" {
+		return flagHeader | flagMessage, nil
+	}
+	var f flag
+	for _, c := range s {
+		switch c {
+		case 'h':
+			f |= flagHeader
+		case 'm':
+			f |= flagMessage
+		default:
+			return 0, fmt.Errorf("unknown flag %q", c)
+		}
+	}
+	return f, nil
+}
+
+// lookup returns the flags that apply to fullMethod, and whether any rule
+// matched at all. A non-wildcard match always wins over "*", regardless of
+// declaration order; among rules of the same specificity, the last-declared
+// one wins.
+func lookup(rules []rule, fullMethod string) (flag, bool) {
+	var (
+		f               flag
+		matched         bool
+		matchedSpecific bool
+	)
+	for _, r := range rules {
+		if !r.matches(fullMethod) {
+			continue
+		}
+		specific := r.full != "*"
+		if matched && matchedSpecific && !specific {
+			// A specific rule already matched; a later wildcard must not
+			// demote it.
+			continue
+		}
+		f = r.flags
+		matched = true
+		matchedSpecific = specific
+	}
+	return f, matched
+}
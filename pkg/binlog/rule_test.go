@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import "testing"
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules("*=h;CniService/Add=hm")
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("len(rules) = %d, want 2", len(rules))
+	}
+
+	flags, ok := lookup(rules, "CniService/Del")
+	if !ok || flags != flagHeader {
+		t.Errorf("CniService/Del: flags=%v ok=%v, want flagHeader/true", flags, ok)
+	}
+
+	flags, ok = lookup(rules, "CniService/Add")
+	if !ok || flags != flagHeader|flagMessage {
+		t.Errorf("CniService/Add: flags=%v ok=%v, want header|message/true", flags, ok)
+	}
+}
+
+func TestLookupSpecificBeatsWildcardRegardlessOfOrder(t *testing.T) {
+	rules, err := parseRules("CniService/Add=hm;*=h")
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+
+	flags, ok := lookup(rules, "CniService/Add")
+	if !ok || flags != flagHeader|flagMessage {
+		t.Errorf("CniService/Add: flags=%v ok=%v, want header|message/true", flags, ok)
+	}
+
+	flags, ok = lookup(rules, "CniService/Del")
+	if !ok || flags != flagHeader {
+		t.Errorf("CniService/Del: flags=%v ok=%v, want flagHeader/true", flags, ok)
+	}
+}
+
+func TestLookupLastDeclaredWinsWithinSameSpecificity(t *testing.T) {
+	rules, err := parseRules("CniService/Add=h;CniService/Add=hm")
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+	if flags, ok := lookup(rules, "CniService/Add"); !ok || flags != flagHeader|flagMessage {
+		t.Errorf("flags=%v ok=%v, want header|message/true", flags, ok)
+	}
+
+	rules, err = parseRules("*=hm;*=h")
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+	if flags, ok := lookup(rules, "anything"); !ok || flags != flagHeader {
+		t.Errorf("flags=%v ok=%v, want flagHeader/true", flags, ok)
+	}
+}
+
+func TestParseRulesEmpty(t *testing.T) {
+	rules, err := parseRules("")
+	if err != nil {
+		t.Fatalf("parseRules(\"\") error = %v", err)
+	}
+	if rules != nil {
+		t.Errorf("parseRules(\"\") = %v, want nil", rules)
+	}
+	if _, ok := lookup(rules, "anything"); ok {
+		t.Errorf("lookup against empty rules should never match")
+	}
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	cases := []string{"no-equals-sign", "CniService/Add=z"}
+	for _, c := range cases {
+		if _, err := parseRules(c); err == nil {
+			t.Errorf("parseRules(%q) expected error, got nil", c)
+		}
+	}
+}
+
+func TestParseRulesDefaultFlags(t *testing.T) {
+	rules, err := parseRules("*=")
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+	flags, ok := lookup(rules, "anything")
+	if !ok || flags != flagHeader|flagMessage {
+		t.Errorf("flags=%v ok=%v, want header|message/true", flags, ok)
+	}
+}
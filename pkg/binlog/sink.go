@@ -0,0 +1,121 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Sink persists entries. Implementations must be safe for concurrent use.
+type Sink interface {
+	Write(e *Entry) error
+	Close() error
+}
+
+// FileSink writes entries to a file, rotating to "<path>.<n>" once the
+// current file reaches maxSizeBytes.
+type FileSink struct {
+	mu           sync.Mutex
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+	f            *os.File
+	size         int64
+}
+
+// NewFileSink opens (or creates) path for appending and rotates it once it
+// grows past maxSizeBytes, keeping at most maxBackups rotated files. A
+// maxSizeBytes of 0 disables rotation; a maxBackups of 0 still bounds the
+// file's size but keeps no backup copies, truncating it in place instead.
+func NewFileSink(path string, maxSizeBytes int64, maxBackups int) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("binlog: cannot open %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("binlog: cannot stat %s: %w", path, err)
+	}
+	return &FileSink{
+		path:         path,
+		maxSizeBytes: maxSizeBytes,
+		maxBackups:   maxBackups,
+		f:            f,
+		size:         info.Size(),
+	}, nil
+}
+
+func (s *FileSink) Write(e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSizeBytes > 0 && s.size >= s.maxSizeBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	n, err := writeEntry(s.f, e)
+	if err != nil {
+		return err
+	}
+	s.size += int64(n)
+	return nil
+}
+
+func (s *FileSink) rotateLocked() error {
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("binlog: cannot close %s for rotation: %w", s.path, err)
+	}
+
+	openFlags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if s.maxBackups <= 0 {
+		// No backups are kept, so there's nowhere to shift the current file
+		// to. Truncate it in place instead of reopening it with O_APPEND,
+		// which would leave it growing forever while s.size thinks it was
+		// reset to 0.
+		openFlags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	} else {
+		for i := s.maxBackups; i > 0; i-- {
+			older := fmt.Sprintf("%s.%d", s.path, i)
+			newer := fmt.Sprintf("%s.%d", s.path, i-1)
+			if i == 1 {
+				newer = s.path
+			}
+			if _, err := os.Stat(newer); err == nil {
+				_ = os.Rename(newer, older)
+			}
+		}
+	}
+
+	f, err := os.OpenFile(s.path, openFlags, 0o600)
+	if err != nil {
+		return fmt.Errorf("binlog: cannot reopen %s after rotation: %w", s.path, err)
+	}
+	s.f = f
+	s.size = 0
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.f.Close(); err != nil {
+		return fmt.Errorf("binlog: cannot close %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "binlog.bin")
+
+	sink, err := NewFileSink(path, 64, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	defer sink.Close()
+
+	entry := &Entry{
+		Timestamp: time.Unix(0, 0),
+		Method:    "CniService/Add",
+		Peer:      "10.0.0.1:1234",
+		Direction: DirectionClientMessage,
+		Payload:   []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := sink.Write(entry); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected current log file to exist: %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected at least one rotated backup: %v", err)
+	}
+}
+
+func TestFileSinkNoRotationWhenDisabled(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "binlog.bin")
+
+	sink, err := NewFileSink(path, 0, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	defer sink.Close()
+
+	entry := &Entry{Timestamp: time.Unix(0, 0), Method: "m", Payload: make([]byte, 1024)}
+	for i := 0; i < 10; i++ {
+		if err := sink.Write(entry); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no rotation when maxSizeBytes is 0, got err=%v", err)
+	}
+}
+
+func TestFileSinkZeroBackupsTruncatesInsteadOfGrowingForever(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "binlog.bin")
+
+	sink, err := NewFileSink(path, 64, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	defer sink.Close()
+
+	entry := &Entry{
+		Timestamp: time.Unix(0, 0),
+		Method:    "CniService/Add",
+		Peer:      "10.0.0.1:1234",
+		Direction: DirectionClientMessage,
+		Payload:   []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := sink.Write(entry); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup file with maxBackups=0, got err=%v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected current log file to exist: %v", err)
+	}
+	// Without the in-place truncation, 20 writes of this entry would leave a
+	// file many times larger than maxSizeBytes instead of bounded near it.
+	if info.Size() > 256 {
+		t.Errorf("file size = %d, want it bounded near maxSizeBytes (got unbounded growth)", info.Size())
+	}
+}
+
+func TestEntryMarshalTruncatesOversizedPayload(t *testing.T) {
+	untruncated := &Entry{Method: "m", Payload: make([]byte, maxPayloadBytes)}
+	oversized := &Entry{Method: "m", Payload: make([]byte, maxPayloadBytes+100)}
+
+	framedUntruncated := untruncated.marshal()
+	framedOversized := oversized.marshal()
+
+	if len(framedOversized) != len(framedUntruncated) {
+		t.Errorf("marshal() did not truncate payload to maxPayloadBytes: got framed len %d, want %d", len(framedOversized), len(framedUntruncated))
+	}
+}
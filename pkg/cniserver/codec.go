@@ -0,0 +1,47 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cniserver is the infra-agent's CNI gRPC server: it implements the
+// CniService RPCs the CNI plugin calls and drives pkg/netconf.
+package cniserver
+
+import "encoding/json"
+
+// marshaler is implemented by the request/response messages in pkg/proto.
+type marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// jsonCodec (de)serializes CniService messages. pkg/proto's message types
+// are plain Go structs rather than protoc-gen-go output, so this codec is
+// applied only to this package's grpc.Server via grpc.ForceServerCodec
+// (see server.go) - it must never be registered under the global "proto"
+// name, which would silently break every other server sharing this binary's
+// real protobuf traffic (e.g. pkg/health_server's grpc.health.v1.Health).
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(marshaler); ok {
+		return m.Marshal()
+	}
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "cni-json"
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cniserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ipdk-io/k8s-infra-offload/pkg/binlog"
+	"github.com/ipdk-io/k8s-infra-offload/pkg/netconf"
+	"github.com/ipdk-io/k8s-infra-offload/pkg/types"
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"gopkg.in/tomb.v2"
+)
+
+// Server is the infra-agent's CNI gRPC server: it receives AddRequest/
+// DelRequest from the CNI plugin and drives pkg/netconf.DoSriovNetwork.
+type Server struct {
+	log     *logrus.Entry
+	grpcSrv *grpc.Server
+	lis     net.Listener
+}
+
+// NewServer builds the CNI server listening on types.InfraAgentAddr:Port.
+// When logger is non-nil, every Add/Del call matching its rules is recorded
+// through binlog.UnaryServerInterceptor, registered as a grpc.UnaryInterceptor the
+// same way it would be on the infra-manager server.
+func NewServer(l *logrus.Entry, logger *binlog.Logger) (types.Server, error) {
+	addr := fmt.Sprintf("%s:%s", types.InfraAgentAddr, types.InfraAgentPort)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen for CNI server on %s: %w", addr, err)
+	}
+
+	grpcSrv := grpc.NewServer(
+		grpc.UnaryInterceptor(binlog.UnaryServerInterceptor(logger)),
+		grpc.ForceServerCodec(jsonCodec{}),
+	)
+	s := &Server{log: l, grpcSrv: grpcSrv, lis: lis}
+	RegisterCniServer(grpcSrv, s)
+	return s, nil
+}
+
+// Add runs the admission webhook chain (via netconf.Validators) and then
+// DoSriovNetwork for req.
+func (s *Server) Add(ctx context.Context, req *pb.AddRequest) (*pb.AddResponse, error) {
+	res := &types.InterfaceInfo{PciAddr: req.GetPciAddress(), InterfaceName: req.GetInterfaceName()}
+	if err := netconf.DoSriovNetwork(req, res); err != nil {
+		s.log.WithError(err).Error("DoSriovNetwork failed")
+		return &pb.AddResponse{Success: false, Error: err.Error()}, nil
+	}
+	return &pb.AddResponse{Success: true}, nil
+}
+
+// Del is a placeholder for host-interface teardown, which pkg/netconf does
+// not yet implement; it is out of scope for this backlog entry.
+func (s *Server) Del(ctx context.Context, req *pb.DelRequest) (*pb.AddResponse, error) {
+	return &pb.AddResponse{Success: true}, nil
+}
+
+func (s *Server) GetName() string {
+	return "cni-server"
+}
+
+func (s *Server) StopServer() {
+	s.grpcSrv.GracefulStop()
+}
+
+func (s *Server) Start(t *tomb.Tomb) error {
+	go func() {
+		if err := s.grpcSrv.Serve(s.lis); err != nil {
+			s.log.Warnf("Error when serving %s error %v", s.GetName(), err)
+		}
+	}()
+	<-t.Dying()
+	s.StopServer()
+	return nil
+}
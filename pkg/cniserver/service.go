@@ -0,0 +1,74 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cniserver
+
+import (
+	"context"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+	"google.golang.org/grpc"
+)
+
+// CniHandler is implemented by the RPC handlers backing CniService.
+type CniHandler interface {
+	Add(ctx context.Context, req *pb.AddRequest) (*pb.AddResponse, error)
+	Del(ctx context.Context, req *pb.DelRequest) (*pb.AddResponse, error)
+}
+
+var cniServiceDesc = grpc.ServiceDesc{
+	ServiceName: "CniService",
+	HandlerType: (*CniHandler)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Add", Handler: addHandler},
+		{MethodName: "Del", Handler: delHandler},
+	},
+	Metadata: "cni.proto",
+}
+
+func addHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.AddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CniHandler).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/CniService/Add"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CniHandler).Add(ctx, req.(*pb.AddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func delHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pb.DelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CniHandler).Del(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/CniService/Del"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CniHandler).Del(ctx, req.(*pb.DelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RegisterCniServer registers h on s, the same way protoc-gen-go-grpc's
+// generated RegisterCniServiceServer would.
+func RegisterCniServer(s *grpc.Server, h CniHandler) {
+	s.RegisterService(&cniServiceDesc, h)
+}
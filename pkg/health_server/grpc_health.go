@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceReadiness is the grpc.health.v1.Health service name that reports
+// SERVING only when every downstream component does. The empty service name
+// ("") is liveness: it reports SERVING as long as the agent process is up,
+// independent of any downstream dependency.
+const ServiceReadiness = "readiness"
+
+// grpcHealthServer implements grpc.health.v1.Health on top of the poller's
+// cache, so Check/Watch never dial a downstream on the request path.
+type grpcHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	p *poller
+}
+
+func newGrpcHealthServer(p *poller) *grpcHealthServer {
+	return &grpcHealthServer{p: p}
+}
+
+func (s *grpcHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return &healthpb.HealthCheckResponse{Status: s.resolve(req.GetService())}, nil
+}
+
+func (s *grpcHealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	service := req.GetService()
+	if service != "" && service != ServiceReadiness {
+		known := false
+		for _, c := range s.p.checks {
+			if c.name == service {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return status.Error(codes.NotFound, "unknown service")
+		}
+	}
+
+	ch := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+	s.p.watch(service, ch)
+	defer s.p.unwatch(service, ch)
+
+	if err := stream.Send(&healthpb.HealthCheckResponse{Status: s.resolve(service)}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case st := <-ch:
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *grpcHealthServer) resolve(service string) healthpb.HealthCheckResponse_ServingStatus {
+	switch service {
+	case "":
+		// Liveness: the grpc server only answers while the process is up.
+		return healthpb.HealthCheckResponse_SERVING
+	case ServiceReadiness:
+		return s.p.readiness()
+	default:
+		return s.p.status(service)
+	}
+}
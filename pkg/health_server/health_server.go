@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -33,95 +34,113 @@ var (
 	grpcDial = grpc.Dial
 )
 
+// defaultPollInterval sets how often components are polled. defaultCacheTTL
+// bounds how stale a cached component status can be before a per-component
+// Check reports SERVICE_UNKNOWN and readiness (and so the HTTP probe) reports
+// NOT_SERVING.
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultCacheTTL     = 15 * time.Second
+)
+
 type httpHealthServer interface {
 	Shutdown(ctx context.Context) error
 	ListenAndServe() error
 }
 
 type healtServer struct {
-	log *logrus.Entry
-	srv httpHealthServer
+	log     *logrus.Entry
+	srv     httpHealthServer
+	grpcSrv *grpc.Server
+	grpcLis net.Listener
+	poller  *poller
 }
 
 func getCheck(hs *healtServer) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		hs.log.Infof("Receive request %v", r)
-		// check status of infra manager
-		if ok := hs.checkInfraManagerLiveness(); !ok {
-			hs.log.Infof("infra manager report failure")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		// check status of cni grpc server status
-		if ok := hs.checkCniServerLiveness(); !ok {
-			hs.log.Infof("CNI reports failure")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		// check status of services server
-		if ok := hs.checkServicesServerStatus(); !ok {
+		if hs.poller.readiness() != healthpb.HealthCheckResponse_SERVING {
 			w.WriteHeader(http.StatusInternalServerError)
-			hs.log.Infof("services server reports failure")
 			return
 		}
-
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+// NewHealthCheckServer builds the agent's health server: an HTTP /check
+// endpoint for existing probes, and a grpc.health.v1.Health service (Check
+// and Watch) for liveness/readiness-aware clients. Both are served off a
+// background poller's cache rather than dialing downstreams per request.
 func NewHealthCheckServer(l *logrus.Entry) (types.Server, error) {
+	checks := []componentCheck{
+		{name: ComponentInfraManager, check: checkInfraManagerLiveness},
+		{name: ComponentCni, check: checkCniServerLiveness},
+		{name: ComponentServices, check: checkServicesServerStatus},
+	}
 	hs := &healtServer{
-		log: l,
+		log:    l,
+		poller: newPoller(l, defaultPollInterval, defaultCacheTTL, checks),
 	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/check", getCheck(hs))
 	hs.srv = &http.Server{
 		Addr:    ":" + types.DefaultHealthServerPort,
 		Handler: mux,
 	}
+
+	lis, err := net.Listen("tcp", ":"+types.HealthGrpcServerPort)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen for health grpc server: %w", err)
+	}
+	hs.grpcLis = lis
+	hs.grpcSrv = grpc.NewServer()
+	healthpb.RegisterHealthServer(hs.grpcSrv, newGrpcHealthServer(hs.poller))
+
 	return hs, nil
 }
 
-func (s *healtServer) checkGrpcServerStatus(target string) bool {
-	s.log.Infof("Checking gRCP service at %s", target)
+func checkGrpcServerStatus(log *logrus.Entry, target string) bool {
+	log.Infof("Checking gRCP service at %s", target)
 	conn, err := grpcDial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	defer func() {
 		if conn == nil {
 			return
 		}
 		if err := conn.Close(); err != nil {
-			s.log.WithError(err).Error("failed to close connection")
+			log.WithError(err).Error("failed to close connection")
 		}
 	}()
 	if err != nil {
-		s.log.WithError(err).Error("Failed to dial gRPC health server")
+		log.WithError(err).Error("Failed to dial gRPC health server")
 		return false
 	}
 	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: ""})
 	if err != nil {
-		s.log.WithError(err).Error("Failed to check gRPC health")
+		log.WithError(err).Error("Failed to check gRPC health")
 		return false
 	}
-	s.log.Infof("Status of gRPC service at %s is: %s", target, resp.GetStatus().String())
+	log.Infof("Status of gRPC service at %s is: %s", target, resp.GetStatus().String())
 	return resp.Status == healthpb.HealthCheckResponse_SERVING
 }
 
-func (s *healtServer) checkInfraManagerLiveness() bool {
+func checkInfraManagerLiveness() bool {
 	managerAddr := fmt.Sprintf("%s:%s", types.InfraManagerAddr, types.InfraManagerPort)
-	return s.checkGrpcServerStatus(managerAddr)
+	return checkGrpcServerStatus(logrus.WithField("func", "checkInfraManagerLiveness"), managerAddr)
 }
 
-func (s *healtServer) checkCniServerLiveness() bool {
+func checkCniServerLiveness() bool {
 	// TODO change this to UDS when grpc start working using it
 	agentAddr := fmt.Sprintf("%s:%s", types.InfraAgentAddr, types.InfraAgentPort)
-	return s.checkGrpcServerStatus(agentAddr)
+	return checkGrpcServerStatus(logrus.WithField("func", "checkCniServerLiveness"), agentAddr)
 }
 
-func (s *healtServer) checkServicesServerStatus() bool {
-	s.log.Infof("Status of services server: %s", types.ServiceServerStatus)
-	return types.ServiceServerStatus == types.ServerStatusOK
+// checkServicesServerStatus reads the services server's status through
+// types.GetServiceServerStatus, which is mutex-protected, rather than
+// reading the underlying package variable directly - that direct read is
+// what raced with the services server's own writes.
+func checkServicesServerStatus() bool {
+	return types.GetServiceServerStatus() == types.ServerStatusOK
 }
 
 func (s *healtServer) GetName() string {
@@ -134,6 +153,8 @@ func (s *healtServer) StopServer() {
 	if err := s.srv.Shutdown(ctx); err != nil {
 		s.log.WithError(err).Error("Failed to close healt server")
 	}
+	s.grpcSrv.GracefulStop()
+	s.poller.stop()
 }
 
 func (s *healtServer) serve() error {
@@ -148,11 +169,17 @@ func (s *healtServer) serve() error {
 }
 
 func (s *healtServer) Start(t *tomb.Tomb) error {
+	s.poller.start()
 	go func() {
 		if err := s.serve(); err != nil {
 			s.log.Warnf("Error when serving %s error %v", s.GetName(), err)
 		}
 	}()
+	go func() {
+		if err := s.grpcSrv.Serve(s.grpcLis); err != nil {
+			s.log.Warnf("Error when serving %s grpc health service error %v", s.GetName(), err)
+		}
+	}()
 	<-t.Dying()
 	// stop server
 	s.StopServer()
@@ -0,0 +1,37 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthserver
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var probeResults = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "infra_agent_health_probe_results_total",
+		Help: "Count of health probe results per component, partitioned by outcome.",
+	},
+	[]string{"component", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(probeResults)
+}
+
+func recordProbeResult(component string, serving bool) {
+	result := "serving"
+	if !serving {
+		result = "not_serving"
+	}
+	probeResults.WithLabelValues(component, result).Inc()
+}
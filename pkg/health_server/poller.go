@@ -0,0 +1,202 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Component names used both as grpc.health.v1.Health service names and as
+// poller cache keys, so Kubernetes probes and sidecars can subscribe to just
+// one downstream instead of the aggregate.
+const (
+	ComponentInfraManager = "infra-manager"
+	ComponentCni          = "cni"
+	ComponentServices     = "services"
+)
+
+// componentCheck is a single downstream liveness probe.
+type componentCheck struct {
+	name  string
+	check func() bool
+}
+
+// poller periodically runs each componentCheck, caches the result for ttl,
+// and pushes changes to any subscribers registered via watch.
+type poller struct {
+	log      *logrus.Entry
+	ttl      time.Duration
+	interval time.Duration
+	checks   []componentCheck
+
+	mu        sync.RWMutex
+	cache     map[string]cachedStatus
+	watchers  map[string][]chan healthpb.HealthCheckResponse_ServingStatus
+	stopCh    chan struct{}
+	stoppedCh chan struct{}
+}
+
+type cachedStatus struct {
+	status    healthpb.HealthCheckResponse_ServingStatus
+	checkedAt time.Time
+}
+
+func newPoller(log *logrus.Entry, interval, ttl time.Duration, checks []componentCheck) *poller {
+	p := &poller{
+		log:       log,
+		ttl:       ttl,
+		interval:  interval,
+		checks:    checks,
+		cache:     make(map[string]cachedStatus, len(checks)),
+		watchers:  make(map[string][]chan healthpb.HealthCheckResponse_ServingStatus),
+		stopCh:    make(chan struct{}),
+		stoppedCh: make(chan struct{}),
+	}
+	for _, c := range checks {
+		p.cache[c.name] = cachedStatus{status: healthpb.HealthCheckResponse_NOT_SERVING}
+	}
+	return p
+}
+
+// start runs the poll loop until stop is called. It runs one poll
+// immediately rather than waiting for the first tick; that poll runs in the
+// background, so requests arriving before it finishes see the initial
+// NOT_SERVING entries.
+func (p *poller) start() {
+	go func() {
+		defer close(p.stoppedCh)
+		p.pollOnce()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.pollOnce()
+			}
+		}
+	}()
+}
+
+func (p *poller) stop() {
+	close(p.stopCh)
+	<-p.stoppedCh
+}
+
+func (p *poller) pollOnce() {
+	for _, c := range p.checks {
+		serving := c.check()
+		recordProbeResult(c.name, serving)
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if serving {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		p.setStatus(c.name, status)
+	}
+}
+
+// status returns the cached status for service. If the cache entry is older
+// than ttl, it reports SERVICE_UNKNOWN rather than stale data.
+func (p *poller) status(service string) healthpb.HealthCheckResponse_ServingStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entry, ok := p.cache[service]
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	if p.ttl > 0 && time.Since(entry.checkedAt) > p.ttl {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	return entry.status
+}
+
+// readiness aggregates every known component: SERVING only if all of them
+// report SERVING on a cache entry no older than ttl. A stale entry - the
+// poll loop stalled or died - degrades readiness the same way status()
+// degrades to SERVICE_UNKNOWN, instead of trusting a last-known-good value
+// forever.
+func (p *poller) readiness() healthpb.HealthCheckResponse_ServingStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, c := range p.checks {
+		entry := p.cache[c.name]
+		if entry.status != healthpb.HealthCheckResponse_SERVING {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if p.ttl > 0 && time.Since(entry.checkedAt) > p.ttl {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+func (p *poller) setStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	prevReady := p.readiness()
+	p.mu.Lock()
+	prev, existed := p.cache[service]
+	p.cache[service] = cachedStatus{status: status, checkedAt: time.Now()}
+	subs := append([]chan healthpb.HealthCheckResponse_ServingStatus(nil), p.watchers[service]...)
+	readySubs := append([]chan healthpb.HealthCheckResponse_ServingStatus(nil), p.watchers[ServiceReadiness]...)
+	p.mu.Unlock()
+
+	// Watchers of the aggregate readiness service are keyed by its own name,
+	// not by a component, so they are notified when the aggregate changes.
+	if ready := p.readiness(); ready != prevReady {
+		for _, ch := range readySubs {
+			select {
+			case ch <- ready:
+			default:
+				p.log.Warnf("watch subscriber for %s is slow, dropping update", ServiceReadiness)
+			}
+		}
+	}
+
+	if existed && prev.status == status {
+		return
+	}
+	for _, ch := range subs {
+		select {
+		case ch <- status:
+		default:
+			p.log.Warnf("watch subscriber for %s is slow, dropping update", service)
+		}
+	}
+}
+
+// watch registers ch to receive every future status change for service.
+// Callers should first read status(service) for the current value, since
+// watch only delivers updates from this point on.
+func (p *poller) watch(service string, ch chan healthpb.HealthCheckResponse_ServingStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.watchers[service] = append(p.watchers[service], ch)
+}
+
+func (p *poller) unwatch(service string, ch chan healthpb.HealthCheckResponse_ServingStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	subs := p.watchers[service]
+	for i, s := range subs {
+		if s == ch {
+			p.watchers[service] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
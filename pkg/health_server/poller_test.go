@@ -0,0 +1,149 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthserver
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestPoller(serving *int32, ttl time.Duration) *poller {
+	return newPoller(logrus.WithField("test", "poller"), time.Hour, ttl, []componentCheck{
+		{name: "comp", check: func() bool { return atomic.LoadInt32(serving) != 0 }},
+	})
+}
+
+func TestPollerCachesStatus(t *testing.T) {
+	var serving int32 = 1
+	p := newTestPoller(&serving, 0)
+	p.pollOnce()
+
+	if got := p.status("comp"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status(comp) = %v, want SERVING", got)
+	}
+
+	atomic.StoreInt32(&serving, 0)
+	// Cache should still report the last polled value until pollOnce runs again.
+	if got := p.status("comp"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status(comp) = %v, want cached SERVING before next poll", got)
+	}
+
+	p.pollOnce()
+	if got := p.status("comp"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status(comp) = %v, want NOT_SERVING after re-poll", got)
+	}
+}
+
+func TestPollerUnknownService(t *testing.T) {
+	var serving int32 = 1
+	p := newTestPoller(&serving, 0)
+	if got := p.status("missing"); got != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("status(missing) = %v, want SERVICE_UNKNOWN", got)
+	}
+}
+
+func TestPollerTTLExpiry(t *testing.T) {
+	var serving int32 = 1
+	p := newTestPoller(&serving, 10*time.Millisecond)
+	p.pollOnce()
+
+	if got := p.status("comp"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("status(comp) = %v, want SERVING immediately after poll", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if got := p.status("comp"); got != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("status(comp) = %v, want SERVICE_UNKNOWN once the TTL has elapsed", got)
+	}
+}
+
+func TestPollerReadiness(t *testing.T) {
+	var a, b int32 = 1, 1
+	p := newPoller(logrus.WithField("test", "poller"), time.Hour, 0, []componentCheck{
+		{name: "a", check: func() bool { return atomic.LoadInt32(&a) != 0 }},
+		{name: "b", check: func() bool { return atomic.LoadInt32(&b) != 0 }},
+	})
+	p.pollOnce()
+	if got := p.readiness(); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("readiness() = %v, want SERVING when all components serve", got)
+	}
+
+	atomic.StoreInt32(&b, 0)
+	p.pollOnce()
+	if got := p.readiness(); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("readiness() = %v, want NOT_SERVING when one component is down", got)
+	}
+}
+
+func TestPollerReadinessDegradesWhenStale(t *testing.T) {
+	var a, b int32 = 1, 1
+	p := newPoller(logrus.WithField("test", "poller"), time.Hour, 10*time.Millisecond, []componentCheck{
+		{name: "a", check: func() bool { return atomic.LoadInt32(&a) != 0 }},
+		{name: "b", check: func() bool { return atomic.LoadInt32(&b) != 0 }},
+	})
+	p.pollOnce()
+	if got := p.readiness(); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("readiness() = %v, want SERVING immediately after poll", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if got := p.readiness(); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("readiness() = %v, want NOT_SERVING once a component's cache entry has gone stale", got)
+	}
+}
+
+func TestPollerWatchNotifiesOnChange(t *testing.T) {
+	var serving int32 = 1
+	p := newTestPoller(&serving, 0)
+	p.pollOnce()
+
+	ch := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+	p.watch("comp", ch)
+	defer p.unwatch("comp", ch)
+
+	atomic.StoreInt32(&serving, 0)
+	p.pollOnce()
+
+	select {
+	case got := <-ch:
+		if got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("watch delivered %v, want NOT_SERVING", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch did not deliver the status change")
+	}
+}
+
+func TestPollerWatchSkipsDuplicateStatus(t *testing.T) {
+	var serving int32 = 1
+	p := newTestPoller(&serving, 0)
+	p.pollOnce()
+
+	ch := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+	p.watch("comp", ch)
+	defer p.unwatch("comp", ch)
+
+	p.pollOnce() // still SERVING, no change
+
+	select {
+	case got := <-ch:
+		t.Fatalf("watch delivered %v for an unchanged status", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
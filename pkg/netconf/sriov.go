@@ -16,6 +16,10 @@ package netconf
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/ipdk-io/k8s-infra-offload/pkg/types"
@@ -24,9 +28,23 @@ import (
 	"github.com/vishvananda/netlink"
 )
 
-func DoSriovNetwork(in *pb.AddRequest, res *types.InterfaceInfo) error {
+// sysBusPciDevices is a var, not a const, so tests can point it at a fake
+// sysfs tree instead of the real /sys.
+var sysBusPciDevices = "/sys/bus/pci/devices"
+
+// DoSriovNetwork returns its error via a named return value so the deferred
+// VF rollback below observes the function's real final error, not just the
+// outcome of applyVfAttributes.
+func DoSriovNetwork(in *pb.AddRequest, res *types.InterfaceInfo) (err error) {
 	logger := log.WithField("func", "DoSriovNetwork").WithField("pkg", "netconf")
 	logger.Infof("Configuring network for pci addr %s name %s", res.PciAddr, res.InterfaceName)
+
+	in, err = runValidators(logger, in)
+	if err != nil {
+		logger.WithError(err).Error("admission webhook rejected AddRequest")
+		return err
+	}
+
 	nn, err := ns.GetNS(in.GetNetns())
 	if err != nil {
 		logger.WithError(err).Errorf("cannot find network namespace %s", in.GetNetns())
@@ -49,6 +67,25 @@ func DoSriovNetwork(in *pb.AddRequest, res *types.InterfaceInfo) error {
 		}
 	}
 
+	if vf := in.GetVfSettings(); vf != nil {
+		var prev *pb.VfSettings
+		prev, err = applyVfAttributes(res.PciAddr, vf)
+		// Deferred before the error check below so a rollback is registered
+		// even when applyVfAttributes itself fails partway through - prev
+		// still reflects whatever was captured before the failing call.
+		defer func() {
+			if err != nil {
+				if rerr := restoreVfAttributes(res.PciAddr, prev); rerr != nil {
+					logger.WithError(rerr).Errorf("cannot roll back VF attributes for pci addr %s", res.PciAddr)
+				}
+			}
+		}()
+		if err != nil {
+			logger.WithError(err).Errorf("cannot apply VF attributes for pci addr %s", res.PciAddr)
+			return err
+		}
+	}
+
 	if err = netlink.LinkSetNsFd(linkObj, int(nn.Fd())); err != nil {
 		logger.WithError(err).Error("Cannot move to given namespace")
 		return err
@@ -59,6 +96,181 @@ func DoSriovNetwork(in *pb.AddRequest, res *types.InterfaceInfo) error {
 	return nil
 }
 
+// vfLocation identifies a VF by its parent PF net device and its index within that PF.
+type vfLocation struct {
+	pfName string
+	vfIdx  int
+}
+
+// resolveVf walks /sys/bus/pci/devices/<pci>/physfn/net/* to find the PF net
+// device owning the VF at pciAddr, then matches virtfn* symlinks under the PF
+// PCI device to recover the VF index netlink expects.
+func resolveVf(pciAddr string) (*vfLocation, error) {
+	physfnNetDir := filepath.Join(sysBusPciDevices, pciAddr, "physfn", "net")
+	entries, err := os.ReadDir(physfnNetDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve PF for VF %s: %w", pciAddr, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no PF net device found for VF %s", pciAddr)
+	}
+	pfName := entries[0].Name()
+
+	pfPciDir := filepath.Join(sysBusPciDevices, pciAddr, "physfn")
+	matches, err := filepath.Glob(filepath.Join(pfPciDir, "virtfn*"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot glob virtfn links for %s: %w", pciAddr, err)
+	}
+	for _, m := range matches {
+		target, err := os.Readlink(m)
+		if err != nil {
+			continue
+		}
+		if filepath.Base(target) != pciAddr {
+			continue
+		}
+		idxStr := strings.TrimPrefix(filepath.Base(m), "virtfn")
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil {
+			continue
+		}
+		return &vfLocation{pfName: pfName, vfIdx: idx}, nil
+	}
+	return nil, fmt.Errorf("cannot find virtfn index for VF %s under PF %s", pciAddr, pfName)
+}
+
+// applyVfAttributes configures rate limiting, trust, spoof check, link state
+// and 802.1Q VLAN tagging on the VF identified by pciAddr. A VLAN request with
+// the 802.1ad (QinQ) protocol is rejected with an error before anything is
+// applied. It returns the VF's prior state so the caller can roll back on a
+// later failure.
+func applyVfAttributes(pciAddr string, vf *pb.VfSettings) (*pb.VfSettings, error) {
+	if vf.GetVlanId() > 0 && vf.GetVlanProto() == pb.VlanProto_VLAN_PROTO_802_1AD {
+		return nil, fmt.Errorf("802.1ad VF VLANs are not supported by the vendored netlink library")
+	}
+
+	loc, err := resolveVf(pciAddr)
+	if err != nil {
+		return nil, err
+	}
+	pfLink, err := netlink.LinkByName(loc.pfName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find PF link %s: %w", loc.pfName, err)
+	}
+
+	prev, err := vfState(pfLink, loc.vfIdx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read prior VF state for %s: %w", pciAddr, err)
+	}
+
+	if vf.GetMinTxRate() > 0 || vf.GetMaxTxRate() > 0 {
+		if err := netlink.LinkSetVfRate(pfLink, loc.vfIdx, int(vf.GetMinTxRate()), int(vf.GetMaxTxRate())); err != nil {
+			return prev, fmt.Errorf("cannot set VF rate: %w", err)
+		}
+	}
+	if vf.HasSpoofChk() {
+		if err := netlink.LinkSetVfSpoofchk(pfLink, loc.vfIdx, vf.GetSpoofChk()); err != nil {
+			return prev, fmt.Errorf("cannot set VF spoofchk: %w", err)
+		}
+	}
+	if vf.HasTrust() {
+		if err := netlink.LinkSetVfTrust(pfLink, loc.vfIdx, vf.GetTrust()); err != nil {
+			return prev, fmt.Errorf("cannot set VF trust: %w", err)
+		}
+	}
+	if vf.GetVlanId() > 0 {
+		if err := netlink.LinkSetVfVlanQos(pfLink, loc.vfIdx, int(vf.GetVlanId()), int(vf.GetVlanQos())); err != nil {
+			return prev, fmt.Errorf("cannot set VF VLAN: %w", err)
+		}
+	}
+	if vf.GetLinkState() != pb.VfLinkState_VF_LINK_STATE_UNSPECIFIED {
+		if err := netlink.LinkSetVfState(pfLink, loc.vfIdx, vfLinkState(vf.GetLinkState())); err != nil {
+			return prev, fmt.Errorf("cannot set VF link state: %w", err)
+		}
+	}
+	return prev, nil
+}
+
+// restoreVfAttributes re-applies a previously captured VF state, used to roll
+// back a partially applied configuration after a failure.
+func restoreVfAttributes(pciAddr string, prev *pb.VfSettings) error {
+	if prev == nil {
+		return nil
+	}
+	loc, err := resolveVf(pciAddr)
+	if err != nil {
+		return err
+	}
+	pfLink, err := netlink.LinkByName(loc.pfName)
+	if err != nil {
+		return fmt.Errorf("cannot find PF link %s: %w", loc.pfName, err)
+	}
+	if err := netlink.LinkSetVfRate(pfLink, loc.vfIdx, int(prev.GetMinTxRate()), int(prev.GetMaxTxRate())); err != nil {
+		return fmt.Errorf("cannot restore VF rate: %w", err)
+	}
+	if err := netlink.LinkSetVfSpoofchk(pfLink, loc.vfIdx, prev.GetSpoofChk()); err != nil {
+		return fmt.Errorf("cannot restore VF spoofchk: %w", err)
+	}
+	if err := netlink.LinkSetVfTrust(pfLink, loc.vfIdx, prev.GetTrust()); err != nil {
+		return fmt.Errorf("cannot restore VF trust: %w", err)
+	}
+	if prev.GetVlanProto() == pb.VlanProto_VLAN_PROTO_802_1AD {
+		return fmt.Errorf("802.1ad VF VLANs are not supported by the vendored netlink library")
+	}
+	if err := netlink.LinkSetVfVlanQos(pfLink, loc.vfIdx, int(prev.GetVlanId()), int(prev.GetVlanQos())); err != nil {
+		return fmt.Errorf("cannot restore VF VLAN: %w", err)
+	}
+	if err := netlink.LinkSetVfState(pfLink, loc.vfIdx, vfLinkState(prev.GetLinkState())); err != nil {
+		return fmt.Errorf("cannot restore VF link state: %w", err)
+	}
+	return nil
+}
+
+// vfState reads the current VF attributes for vfIdx off pfLink so they can be
+// restored if configuration fails partway through.
+func vfState(pfLink netlink.Link, vfIdx int) (*pb.VfSettings, error) {
+	attrs := pfLink.Attrs()
+	for _, v := range attrs.Vfs {
+		if v.ID != vfIdx {
+			continue
+		}
+		spoofChk := v.Spoofchk
+		trust := v.Trust != 0
+		return &pb.VfSettings{
+			MinTxRate: uint32(v.MinTxRate),
+			MaxTxRate: uint32(v.MaxTxRate),
+			SpoofChk:  &spoofChk,
+			Trust:     &trust,
+			VlanId:    uint32(v.Vlan),
+			VlanQos:   uint32(v.Qos),
+			LinkState: vfLinkStateFromNetlink(v.LinkState),
+		}, nil
+	}
+	return nil, fmt.Errorf("VF %d not found on link %s", vfIdx, attrs.Name)
+}
+
+func vfLinkState(s pb.VfLinkState) uint32 {
+	switch s {
+	case pb.VfLinkState_VF_LINK_STATE_ENABLE:
+		return netlink.VF_LINK_STATE_ENABLE
+	case pb.VfLinkState_VF_LINK_STATE_DISABLE:
+		return netlink.VF_LINK_STATE_DISABLE
+	default:
+		return netlink.VF_LINK_STATE_AUTO
+	}
+}
+
+func vfLinkStateFromNetlink(s uint32) pb.VfLinkState {
+	switch s {
+	case netlink.VF_LINK_STATE_ENABLE:
+		return pb.VfLinkState_VF_LINK_STATE_ENABLE
+	case netlink.VF_LINK_STATE_DISABLE:
+		return pb.VfLinkState_VF_LINK_STATE_DISABLE
+	default:
+		return pb.VfLinkState_VF_LINK_STATE_AUTO
+	}
+}
+
 func configureSriovNamespace(in *pb.AddRequest, linkObj netlink.Link) error {
 	return ns.WithNetNSPath(in.Netns, func(nn ns.NetNS) error {
 		if err := netlink.LinkSetName(linkObj, in.InterfaceName); err != nil {
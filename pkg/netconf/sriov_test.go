@@ -0,0 +1,132 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	testPfPci = "0000:3b:00.0"
+	testVfPci = "0000:3b:02.1"
+	testPfNet = "eth1"
+)
+
+// writeFakeSysfs builds a minimal /sys/bus/pci/devices tree for one PF with
+// one VF, wired up the same way the real sysfs layout is: the VF's
+// "physfn" symlink points at the PF, and the PF's "virtfnN" symlink points
+// back at the VF.
+func writeFakeSysfs(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	pfDir := filepath.Join(root, testPfPci)
+	if err := os.MkdirAll(filepath.Join(pfDir, "net", testPfNet), 0o755); err != nil {
+		t.Fatalf("cannot create fake PF net dir: %v", err)
+	}
+
+	vfDir := filepath.Join(root, testVfPci)
+	if err := os.MkdirAll(vfDir, 0o755); err != nil {
+		t.Fatalf("cannot create fake VF dir: %v", err)
+	}
+
+	if err := os.Symlink(filepath.Join("..", testPfPci), filepath.Join(vfDir, "physfn")); err != nil {
+		t.Fatalf("cannot symlink physfn: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..", testVfPci), filepath.Join(pfDir, "virtfn0")); err != nil {
+		t.Fatalf("cannot symlink virtfn0: %v", err)
+	}
+
+	return root
+}
+
+func TestResolveVf(t *testing.T) {
+	old := sysBusPciDevices
+	sysBusPciDevices = writeFakeSysfs(t)
+	defer func() { sysBusPciDevices = old }()
+
+	loc, err := resolveVf(testVfPci)
+	if err != nil {
+		t.Fatalf("resolveVf() error = %v", err)
+	}
+	if loc.pfName != testPfNet {
+		t.Errorf("pfName = %q, want %q", loc.pfName, testPfNet)
+	}
+	if loc.vfIdx != 0 {
+		t.Errorf("vfIdx = %d, want 0", loc.vfIdx)
+	}
+}
+
+func TestResolveVfNoPf(t *testing.T) {
+	old := sysBusPciDevices
+	sysBusPciDevices = t.TempDir()
+	defer func() { sysBusPciDevices = old }()
+
+	if _, err := resolveVf("0000:99:00.0"); err == nil {
+		t.Fatal("resolveVf() expected error for VF with no PF, got nil")
+	}
+}
+
+func TestApplyVfAttributesRejects8021ad(t *testing.T) {
+	vf := &pb.VfSettings{VlanId: 100, VlanProto: pb.VlanProto_VLAN_PROTO_802_1AD}
+	if _, err := applyVfAttributes(testVfPci, vf); err == nil {
+		t.Fatal("applyVfAttributes() expected error for unsupported 802.1ad VLAN, got nil")
+	}
+}
+
+func TestVfLinkStateRoundTrip(t *testing.T) {
+	cases := []pb.VfLinkState{
+		pb.VfLinkState_VF_LINK_STATE_AUTO,
+		pb.VfLinkState_VF_LINK_STATE_ENABLE,
+		pb.VfLinkState_VF_LINK_STATE_DISABLE,
+	}
+	for _, want := range cases {
+		got := vfLinkStateFromNetlink(vfLinkState(want))
+		if got != want {
+			t.Errorf("round trip of %v = %v", want, got)
+		}
+	}
+}
+
+func TestVfStatePresence(t *testing.T) {
+	link := &netlink.Dummy{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: testPfNet,
+			Vfs: []netlink.VfInfo{
+				{ID: 0, Spoofchk: true, Trust: 0, MinTxRate: 10, MaxTxRate: 100},
+			},
+		},
+	}
+
+	got, err := vfState(link, 0)
+	if err != nil {
+		t.Fatalf("vfState() error = %v", err)
+	}
+	if !got.HasSpoofChk() || !got.GetSpoofChk() {
+		t.Errorf("GetSpoofChk() = %v, HasSpoofChk() = %v, want true/true", got.GetSpoofChk(), got.HasSpoofChk())
+	}
+	if !got.HasTrust() || got.GetTrust() {
+		t.Errorf("GetTrust() = %v, HasTrust() = %v, want false/true", got.GetTrust(), got.HasTrust())
+	}
+
+	if _, err := vfState(link, 7); err == nil {
+		t.Fatal("vfState() expected error for unknown VF index, got nil")
+	}
+}
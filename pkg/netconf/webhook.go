@@ -0,0 +1,51 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netconf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipdk-io/k8s-infra-offload/pkg/webhook"
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+// Validators, if non-empty, are run against every AddRequest before
+// DoSriovNetwork applies it - this is the admission webhook chain, set up
+// by the agent at startup from its configured webhook.Validator set.
+var Validators []webhook.Validator
+
+// runValidators runs the configured validator chain against in, returning
+// the (possibly mutated) request to apply, or an error if any validator
+// rejected it.
+func runValidators(logger interface{ Warn(args ...interface{}) }, in *pb.AddRequest) (*pb.AddRequest, error) {
+	if len(Validators) == 0 {
+		return in, nil
+	}
+	verdict, err := webhook.Chain(context.Background(), Validators, in)
+	if err != nil {
+		return nil, fmt.Errorf("admission webhook chain failed: %w", err)
+	}
+	if !verdict.Allowed {
+		return nil, fmt.Errorf("request rejected by admission webhook: %s", verdict.Reason)
+	}
+	if verdict.Reason != "" {
+		logger.Warn(verdict.Reason)
+	}
+	if verdict.Mutated != nil {
+		return verdict.Mutated, nil
+	}
+	return in, nil
+}
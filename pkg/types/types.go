@@ -0,0 +1,82 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package types holds the small set of shared types and addresses used
+// across the agent's servers and network configuration code, so none of
+// them need to import each other directly.
+package types
+
+import (
+	"sync"
+
+	"gopkg.in/tomb.v2"
+)
+
+// Addresses and ports the agent's gRPC servers listen on and dial.
+const (
+	DefaultHealthServerPort = "8080"
+	// HealthGrpcServerPort serves grpc.health.v1.Health (Check/Watch),
+	// separate from DefaultHealthServerPort's HTTP /check.
+	HealthGrpcServerPort = "8081"
+
+	InfraManagerAddr = "127.0.0.1"
+	InfraManagerPort = "50051"
+
+	InfraAgentAddr = "127.0.0.1"
+	InfraAgentPort = "50052"
+)
+
+// Server is implemented by every long-running component the agent starts,
+// so they can all be driven the same way from a tomb.Tomb.
+type Server interface {
+	GetName() string
+	Start(t *tomb.Tomb) error
+	StopServer()
+}
+
+// InterfaceInfo describes the host interface DoSriovNetwork is configuring.
+type InterfaceInfo struct {
+	PciAddr       string
+	InterfaceName string
+}
+
+// ServerStatus is the coarse up/down status of one of the agent's servers.
+type ServerStatus int
+
+const (
+	ServerStatusUnknown ServerStatus = iota
+	ServerStatusOK
+	ServerStatusNotOK
+)
+
+var (
+	serviceServerStatusMu sync.RWMutex
+	serviceServerStatus   = ServerStatusUnknown
+)
+
+// GetServiceServerStatus returns the services server's last reported
+// status. Safe for concurrent use with SetServiceServerStatus.
+func GetServiceServerStatus() ServerStatus {
+	serviceServerStatusMu.RLock()
+	defer serviceServerStatusMu.RUnlock()
+	return serviceServerStatus
+}
+
+// SetServiceServerStatus records the services server's current status.
+// Safe for concurrent use with GetServiceServerStatus.
+func SetServiceServerStatus(s ServerStatus) {
+	serviceServerStatusMu.Lock()
+	defer serviceServerStatusMu.Unlock()
+	serviceServerStatus = s
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+// PciRange is an inclusive range of PCI bus addresses on the 0000:bb:dd.f
+// form, compared lexicographically within a common bus prefix.
+type PciRange struct {
+	From string
+	To   string
+}
+
+// PolicyConfig mirrors the constraints an SriovNetworkNodePolicy expresses
+// for a node: which PCI addresses may be handed out, the MTU bounds pods may
+// request, and the pod labels required before a VF is attached.
+type PolicyConfig struct {
+	// AllowedPciRanges restricts which PCI addresses DoSriovNetwork may
+	// configure on this node. An empty slice allows any address.
+	AllowedPciRanges []PciRange
+	// MinMtu and MaxMtu bound the MTU a pod may request. Zero disables the
+	// corresponding bound.
+	MinMtu int
+	MaxMtu int
+	// RequiredPodLabels must all be present (by key) on the requesting pod.
+	RequiredPodLabels []string
+	// DefaultConfigName, if set, names an AddRequest.InterfaceName that is
+	// immutable: deletes are allowed but reported back as a warning rather
+	// than a hard rejection.
+	DefaultConfigName string
+}
+
+// PolicyValidator is the built-in Validator enforcing PolicyConfig. It never
+// mutates the request; it only allows, warns, or rejects.
+type PolicyValidator struct {
+	cfg PolicyConfig
+}
+
+// NewPolicyValidator builds the built-in node policy validator.
+func NewPolicyValidator(cfg PolicyConfig) *PolicyValidator {
+	return &PolicyValidator{cfg: cfg}
+}
+
+func (v *PolicyValidator) Name() string {
+	return "node-policy"
+}
+
+func (v *PolicyValidator) Validate(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+	if req.GetIsDelete() {
+		if v.cfg.DefaultConfigName != "" && req.GetInterfaceName() == v.cfg.DefaultConfigName {
+			return &Verdict{
+				Allowed: true,
+				Reason:  fmt.Sprintf("%s is the default config and should not normally be removed", v.cfg.DefaultConfigName),
+			}, nil
+		}
+		return &Verdict{Allowed: true}, nil
+	}
+
+	if len(v.cfg.AllowedPciRanges) > 0 {
+		if !pciAllowed(v.cfg.AllowedPciRanges, req.GetPciAddress()) {
+			return &Verdict{
+				Allowed: false,
+				Reason:  fmt.Sprintf("PCI address %s is outside the node's allowed ranges", req.GetPciAddress()),
+			}, nil
+		}
+	}
+
+	mtu := int(req.GetSettings().GetMtu())
+	if mtu > 0 {
+		if v.cfg.MinMtu > 0 && mtu < v.cfg.MinMtu {
+			return &Verdict{Allowed: false, Reason: fmt.Sprintf("MTU %d is below the node minimum of %d", mtu, v.cfg.MinMtu)}, nil
+		}
+		if v.cfg.MaxMtu > 0 && mtu > v.cfg.MaxMtu {
+			return &Verdict{Allowed: false, Reason: fmt.Sprintf("MTU %d exceeds the node maximum of %d", mtu, v.cfg.MaxMtu)}, nil
+		}
+	}
+
+	for _, key := range v.cfg.RequiredPodLabels {
+		if _, ok := req.GetPodLabels()[key]; !ok {
+			return &Verdict{Allowed: false, Reason: fmt.Sprintf("pod is missing required label %q", key)}, nil
+		}
+	}
+
+	return &Verdict{Allowed: true}, nil
+}
+
+// pciAllowed reports whether addr falls within any of ranges, comparing
+// lexicographically since PCI addresses sort correctly as strings within a
+// fixed-width "0000:bb:dd.f" form.
+func pciAllowed(ranges []PciRange, addr string) bool {
+	if addr == "" {
+		return false
+	}
+	for _, r := range ranges {
+		if addr >= r.From && addr <= r.To {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+func TestPolicyValidatorPciRange(t *testing.T) {
+	v := NewPolicyValidator(PolicyConfig{
+		AllowedPciRanges: []PciRange{{From: "0000:3b:00.0", To: "0000:3b:1f.7"}},
+	})
+
+	allowed := &pb.AddRequest{PciAddress: "0000:3b:02.1"}
+	verdict, err := v.Validate(context.Background(), allowed)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !verdict.Allowed {
+		t.Errorf("PCI address inside the allowed range was rejected: %s", verdict.Reason)
+	}
+
+	rejected := &pb.AddRequest{PciAddress: "0000:5e:00.0"}
+	verdict, err = v.Validate(context.Background(), rejected)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if verdict.Allowed {
+		t.Errorf("PCI address outside the allowed range was allowed")
+	}
+}
+
+func TestPolicyValidatorMtuBounds(t *testing.T) {
+	v := NewPolicyValidator(PolicyConfig{MinMtu: 1280, MaxMtu: 9000})
+
+	for _, mtu := range []uint32{1280, 1500, 9000} {
+		req := &pb.AddRequest{Settings: &pb.Settings{Mtu: mtu}}
+		verdict, err := v.Validate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if !verdict.Allowed {
+			t.Errorf("MTU %d within bounds was rejected: %s", mtu, verdict.Reason)
+		}
+	}
+
+	for _, mtu := range []uint32{68, 65536} {
+		req := &pb.AddRequest{Settings: &pb.Settings{Mtu: mtu}}
+		verdict, err := v.Validate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if verdict.Allowed {
+			t.Errorf("MTU %d outside bounds was allowed", mtu)
+		}
+	}
+}
+
+func TestPolicyValidatorRequiredLabels(t *testing.T) {
+	v := NewPolicyValidator(PolicyConfig{RequiredPodLabels: []string{"app"}})
+
+	req := &pb.AddRequest{PodLabels: map[string]string{"app": "web"}}
+	verdict, err := v.Validate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !verdict.Allowed {
+		t.Errorf("request with the required label was rejected: %s", verdict.Reason)
+	}
+
+	req = &pb.AddRequest{PodLabels: map[string]string{"other": "x"}}
+	verdict, err = v.Validate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if verdict.Allowed {
+		t.Errorf("request missing the required label was allowed")
+	}
+}
+
+func TestPolicyValidatorImmutableDefaultConfigWarnsOnDelete(t *testing.T) {
+	v := NewPolicyValidator(PolicyConfig{DefaultConfigName: "default-net"})
+
+	req := &pb.AddRequest{InterfaceName: "default-net", IsDelete: true}
+	verdict, err := v.Validate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !verdict.Allowed {
+		t.Errorf("delete of the default config should warn, not reject")
+	}
+	if verdict.Reason == "" {
+		t.Errorf("delete of the default config should carry a warning Reason")
+	}
+}
+
+func TestPolicyValidatorOrdinaryDeleteAlwaysAllowed(t *testing.T) {
+	v := NewPolicyValidator(PolicyConfig{
+		DefaultConfigName: "default-net",
+		AllowedPciRanges:  []PciRange{{From: "0000:3b:00.0", To: "0000:3b:1f.7"}},
+	})
+
+	req := &pb.AddRequest{InterfaceName: "net1", PciAddress: "0000:99:00.0", IsDelete: true}
+	verdict, err := v.Validate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !verdict.Allowed {
+		t.Errorf("deletes should bypass PCI/MTU/label checks, got rejected: %s", verdict.Reason)
+	}
+}
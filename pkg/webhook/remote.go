@@ -0,0 +1,101 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+// remoteRequest is the payload POSTed to a remote validator.
+type remoteRequest struct {
+	AddRequest json.RawMessage `json:"addRequest"`
+}
+
+// remoteResponse is the payload a remote validator must return.
+type remoteResponse struct {
+	Allowed bool            `json:"allowed"`
+	Reason  string          `json:"reason"`
+	Mutated json.RawMessage `json:"mutated,omitempty"`
+}
+
+// RemoteValidator forwards the AddRequest to an HTTPS endpoint and maps the
+// JSON response back to a Verdict.
+type RemoteValidator struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewRemoteValidator builds a validator that POSTs to url using client. A nil
+// client defaults to a 5s-timeout http.Client.
+func NewRemoteValidator(name, url string, client *http.Client) *RemoteValidator {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &RemoteValidator{name: name, url: url, client: client}
+}
+
+func (v *RemoteValidator) Name() string {
+	return v.name
+}
+
+func (v *RemoteValidator) Validate(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+	body, err := req.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("webhook: cannot marshal AddRequest for %s: %w", v.name, err)
+	}
+	payload, err := json.Marshal(remoteRequest{AddRequest: body})
+	if err != nil {
+		return nil, fmt.Errorf("webhook: cannot marshal request envelope for %s: %w", v.name, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: cannot build request for %s: %w", v.name, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: validator %s unreachable: %w", v.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("webhook: validator %s returned status %d", v.name, resp.StatusCode)
+	}
+
+	var out remoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("webhook: cannot decode response from %s: %w", v.name, err)
+	}
+
+	verdict := &Verdict{Allowed: out.Allowed, Reason: out.Reason}
+	if len(out.Mutated) > 0 {
+		mutated := &pb.AddRequest{}
+		if err := json.Unmarshal(out.Mutated, mutated); err != nil {
+			return nil, fmt.Errorf("webhook: cannot unmarshal mutation from %s: %w", v.name, err)
+		}
+		verdict.Mutated = mutated
+	}
+	return verdict, nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ipdk-io/k8s-infra-offload/pkg/types"
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/tomb.v2"
+)
+
+// Server runs the admission HTTPS endpoint the CNI server consults before
+// DoSriovNetwork or host-interface setup executes.
+type Server struct {
+	log        *logrus.Entry
+	srv        *http.Server
+	validators []Validator
+	certPath   string
+	keyPath    string
+}
+
+// Config configures the admission webhook server.
+type Config struct {
+	Addr       string
+	CertPath   string
+	KeyPath    string
+	Validators []Validator
+}
+
+// NewServer builds a webhook Server. certPath/keyPath are reloaded from disk
+// on every TLS handshake, so a rotated certificate takes effect without a
+// restart.
+func NewServer(cfg Config, l *logrus.Entry) (types.Server, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("webhook: Addr must be set")
+	}
+	s := &Server{
+		log:        l,
+		validators: cfg.Validators,
+		certPath:   cfg.CertPath,
+		keyPath:    cfg.KeyPath,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", s.handleValidate)
+	s.srv = &http.Server{
+		Addr:    cfg.Addr,
+		Handler: mux,
+		TLSConfig: &tls.Config{
+			GetCertificate: s.getCertificate,
+			MinVersion:     tls.VersionTLS12,
+		},
+	}
+	return s, nil
+}
+
+func (s *Server) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(s.certPath, s.keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: cannot load certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var req remoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("cannot decode request: %v", err), http.StatusBadRequest)
+		return
+	}
+	addReq := &pb.AddRequest{}
+	if err := json.Unmarshal(req.AddRequest, addReq); err != nil {
+		http.Error(w, fmt.Sprintf("cannot unmarshal AddRequest: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	verdict, err := Chain(ctx, s.validators, addReq)
+	if err != nil {
+		s.log.WithError(err).Error("validator chain failed")
+		http.Error(w, fmt.Sprintf("validation error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resp := remoteResponse{Allowed: verdict.Allowed, Reason: verdict.Reason}
+	if verdict.Mutated != nil {
+		mutated, err := verdict.Mutated.Marshal()
+		if err != nil {
+			s.log.WithError(err).Error("cannot marshal mutated AddRequest")
+			http.Error(w, fmt.Sprintf("cannot marshal response: %v", err), http.StatusInternalServerError)
+			return
+		}
+		resp.Mutated = mutated
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log.WithError(err).Error("cannot encode validation response")
+	}
+}
+
+func (s *Server) GetName() string {
+	return "admission-webhook"
+}
+
+func (s *Server) StopServer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.srv.Shutdown(ctx); err != nil {
+		s.log.WithError(err).Error("Failed to close webhook server")
+	}
+}
+
+func (s *Server) serve() error {
+	// cert/key paths are empty here because TLSConfig.GetCertificate already
+	// reloads them from disk on every handshake.
+	err := s.srv.ListenAndServeTLS("", "")
+	if errors.Is(err, http.ErrServerClosed) {
+		s.log.Infof("webhook server is closed")
+	} else if err != nil {
+		s.log.WithError(err).Error("Error listening for webhook server")
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Start(t *tomb.Tomb) error {
+	go func() {
+		if err := s.serve(); err != nil {
+			s.log.Warnf("Error when serving %s error %v", s.GetName(), err)
+		}
+	}()
+	<-t.Dying()
+	s.StopServer()
+	return nil
+}
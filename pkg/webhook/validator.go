@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook runs an admission-style validation step in front of the
+// CNI server: before DoSriovNetwork or host-interface setup executes, the
+// AddRequest is handed to a configured set of validators, any of which may
+// reject the call (surfaced back through the CNI reply) or mutate fields
+// such as MTU, routes, or requested VF attributes.
+package webhook
+
+import (
+	"context"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+// Verdict is a validator's decision on one AddRequest.
+type Verdict struct {
+	// Allowed is false if the request must be rejected.
+	Allowed bool
+	// Reason should be set whenever Allowed is false; a rejection's Reason
+	// is surfaced back through the CNI reply. It may also carry a warning
+	// when Allowed is true (e.g. a delete of an immutable default config);
+	// DoSriovNetwork logs such a warning and does not return it in the reply.
+	Reason string
+	// Mutated, if non-nil, replaces the AddRequest passed to the next
+	// validator and ultimately to DoSriovNetwork.
+	Mutated *pb.AddRequest
+}
+
+// Validator inspects (and may rewrite) an AddRequest before it is applied.
+type Validator interface {
+	Name() string
+	Validate(ctx context.Context, req *pb.AddRequest) (*Verdict, error)
+}
+
+// Chain runs each validator in order, feeding any mutation forward into the
+// next validator, and stops at the first rejection.
+func Chain(ctx context.Context, validators []Validator, req *pb.AddRequest) (*Verdict, error) {
+	current := req
+	var lastReason string
+	for _, v := range validators {
+		verdict, err := v.Validate(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		if !verdict.Allowed {
+			return verdict, nil
+		}
+		if verdict.Mutated != nil {
+			current = verdict.Mutated
+		}
+		if verdict.Reason != "" {
+			lastReason = verdict.Reason
+		}
+	}
+	return &Verdict{Allowed: true, Reason: lastReason, Mutated: current}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "github.com/ipdk-io/k8s-infra-offload/proto"
+)
+
+type fakeValidator struct {
+	name    string
+	verdict *Verdict
+	err     error
+}
+
+func (f *fakeValidator) Name() string { return f.name }
+
+func (f *fakeValidator) Validate(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+	return f.verdict, f.err
+}
+
+func TestChainAllAllow(t *testing.T) {
+	req := &pb.AddRequest{InterfaceName: "net1"}
+	v1 := &fakeValidator{name: "a", verdict: &Verdict{Allowed: true}}
+	v2 := &fakeValidator{name: "b", verdict: &Verdict{Allowed: true}}
+
+	verdict, err := Chain(context.Background(), []Validator{v1, v2}, req)
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if !verdict.Allowed {
+		t.Errorf("Allowed = false, want true")
+	}
+}
+
+func TestChainStopsAtFirstRejection(t *testing.T) {
+	req := &pb.AddRequest{InterfaceName: "net1"}
+	called := false
+	v1 := &fakeValidator{name: "a", verdict: &Verdict{Allowed: false, Reason: "nope"}}
+	v2 := &fakeValidator{name: "b", verdict: &Verdict{Allowed: true}}
+
+	// v2 must never run once v1 rejects; wrap it to detect a call.
+	wrapped := validatorFunc(func(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+		called = true
+		return v2.verdict, nil
+	})
+
+	verdict, err := Chain(context.Background(), []Validator{v1, wrapped}, req)
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if verdict.Allowed {
+		t.Errorf("Allowed = true, want false")
+	}
+	if verdict.Reason != "nope" {
+		t.Errorf("Reason = %q, want %q", verdict.Reason, "nope")
+	}
+	if called {
+		t.Errorf("second validator ran after the first rejected the request")
+	}
+}
+
+func TestChainThreadsMutationForward(t *testing.T) {
+	req := &pb.AddRequest{InterfaceName: "net1", Settings: &pb.Settings{Mtu: 1500}}
+	mutated := &pb.AddRequest{InterfaceName: "net1", Settings: &pb.Settings{Mtu: 9000}}
+
+	var seenMtu uint32
+	v1 := &fakeValidator{name: "mutator", verdict: &Verdict{Allowed: true, Mutated: mutated}}
+	v2 := validatorFunc(func(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+		seenMtu = req.GetSettings().GetMtu()
+		return &Verdict{Allowed: true}, nil
+	})
+
+	if _, err := Chain(context.Background(), []Validator{v1, v2}, req); err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if seenMtu != 9000 {
+		t.Errorf("second validator saw Mtu = %d, want 9000 (the mutated value)", seenMtu)
+	}
+}
+
+func TestChainPropagatesValidatorError(t *testing.T) {
+	req := &pb.AddRequest{}
+	v1 := &fakeValidator{name: "a", err: errors.New("unreachable")}
+	if _, err := Chain(context.Background(), []Validator{v1}, req); err == nil {
+		t.Fatal("Chain() expected error, got nil")
+	}
+}
+
+// validatorFunc adapts a function to the Validator interface for tests.
+type validatorFunc func(ctx context.Context, req *pb.AddRequest) (*Verdict, error)
+
+func (f validatorFunc) Name() string { return "func" }
+
+func (f validatorFunc) Validate(ctx context.Context, req *pb.AddRequest) (*Verdict, error) {
+	return f(ctx, req)
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2022 Intel Corporation.  All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License")
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proto holds the Go bindings for cni.proto: the messages exchanged
+// between the CNI plugin and the infra-agent CNI server.
+package proto
+
+import "encoding/json"
+
+// Settings carries interface-level options requested for the pod interface.
+type Settings struct {
+	Mtu uint32 `json:"mtu,omitempty"`
+}
+
+func (s *Settings) GetMtu() uint32 {
+	if s == nil {
+		return 0
+	}
+	return s.Mtu
+}
+
+// Route is one route to be installed in the pod network namespace.
+type Route struct {
+	Dst string `json:"dst,omitempty"`
+	Gw  string `json:"gw,omitempty"`
+}
+
+func (r *Route) GetDst() string {
+	if r == nil {
+		return ""
+	}
+	return r.Dst
+}
+
+func (r *Route) GetGw() string {
+	if r == nil {
+		return ""
+	}
+	return r.Gw
+}
+
+// VfLinkState names netlink's IFLA_VF_LINK_STATE_* states, plus UNSPECIFIED
+// for "not requested". The numbers differ from netlink's (AUTO is 1 here, 0
+// in netlink); pkg/netconf maps between the two.
+type VfLinkState int32
+
+const (
+	VfLinkState_VF_LINK_STATE_UNSPECIFIED VfLinkState = 0
+	VfLinkState_VF_LINK_STATE_AUTO        VfLinkState = 1
+	VfLinkState_VF_LINK_STATE_ENABLE      VfLinkState = 2
+	VfLinkState_VF_LINK_STATE_DISABLE     VfLinkState = 3
+)
+
+var vfLinkStateNames = map[VfLinkState]string{
+	VfLinkState_VF_LINK_STATE_UNSPECIFIED: "VF_LINK_STATE_UNSPECIFIED",
+	VfLinkState_VF_LINK_STATE_AUTO:        "VF_LINK_STATE_AUTO",
+	VfLinkState_VF_LINK_STATE_ENABLE:      "VF_LINK_STATE_ENABLE",
+	VfLinkState_VF_LINK_STATE_DISABLE:     "VF_LINK_STATE_DISABLE",
+}
+
+func (s VfLinkState) String() string {
+	if name, ok := vfLinkStateNames[s]; ok {
+		return name
+	}
+	return "VF_LINK_STATE_UNSPECIFIED"
+}
+
+// VlanProto selects the VLAN tag protocol for VF VLAN/QinQ tagging.
+type VlanProto int32
+
+const (
+	VlanProto_VLAN_PROTO_802_1Q  VlanProto = 0
+	VlanProto_VLAN_PROTO_802_1AD VlanProto = 1
+)
+
+func (p VlanProto) String() string {
+	if p == VlanProto_VLAN_PROTO_802_1AD {
+		return "VLAN_PROTO_802_1AD"
+	}
+	return "VLAN_PROTO_802_1Q"
+}
+
+// VfSettings carries the per-VF QoS attributes the sriov-network-operator
+// ecosystem exposes through pod annotations.
+//
+// SpoofChk and Trust are *bool, not bool: a nil pointer means "not
+// requested", distinct from an explicit false. Callers must check
+// HasSpoofChk/HasTrust before applying either field, the same way the other
+// fields are only applied when their zero value wouldn't be a meaningful
+// request (MinTxRate/MaxTxRate > 0, VlanId > 0, LinkState != UNSPECIFIED).
+type VfSettings struct {
+	MinTxRate uint32      `json:"minTxRate,omitempty"`
+	MaxTxRate uint32      `json:"maxTxRate,omitempty"`
+	SpoofChk  *bool       `json:"spoofChk,omitempty"`
+	Trust     *bool       `json:"trust,omitempty"`
+	LinkState VfLinkState `json:"linkState,omitempty"`
+	VlanId    uint32      `json:"vlanId,omitempty"`
+	VlanQos   uint32      `json:"vlanQos,omitempty"`
+	VlanProto VlanProto   `json:"vlanProto,omitempty"`
+}
+
+func (v *VfSettings) GetMinTxRate() uint32 {
+	if v == nil {
+		return 0
+	}
+	return v.MinTxRate
+}
+
+func (v *VfSettings) GetMaxTxRate() uint32 {
+	if v == nil {
+		return 0
+	}
+	return v.MaxTxRate
+}
+
+// HasSpoofChk reports whether SpoofChk was explicitly requested.
+func (v *VfSettings) HasSpoofChk() bool {
+	return v != nil && v.SpoofChk != nil
+}
+
+func (v *VfSettings) GetSpoofChk() bool {
+	if v == nil || v.SpoofChk == nil {
+		return false
+	}
+	return *v.SpoofChk
+}
+
+// HasTrust reports whether Trust was explicitly requested.
+func (v *VfSettings) HasTrust() bool {
+	return v != nil && v.Trust != nil
+}
+
+func (v *VfSettings) GetTrust() bool {
+	if v == nil || v.Trust == nil {
+		return false
+	}
+	return *v.Trust
+}
+
+func (v *VfSettings) GetLinkState() VfLinkState {
+	if v == nil {
+		return VfLinkState_VF_LINK_STATE_UNSPECIFIED
+	}
+	return v.LinkState
+}
+
+func (v *VfSettings) GetVlanId() uint32 {
+	if v == nil {
+		return 0
+	}
+	return v.VlanId
+}
+
+func (v *VfSettings) GetVlanQos() uint32 {
+	if v == nil {
+		return 0
+	}
+	return v.VlanQos
+}
+
+func (v *VfSettings) GetVlanProto() VlanProto {
+	if v == nil {
+		return VlanProto_VLAN_PROTO_802_1Q
+	}
+	return v.VlanProto
+}
+
+// BoolPtr is a convenience constructor for VfSettings.SpoofChk/Trust, which
+// need an explicit presence signal rather than a plain bool.
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// AddRequest is the CNI ADD request sent to the infra-agent CNI server.
+type AddRequest struct {
+	Netns           string            `json:"netns,omitempty"`
+	InterfaceName   string            `json:"interfaceName,omitempty"`
+	ContainerIps    []string          `json:"containerIps,omitempty"`
+	ContainerRoutes []*Route          `json:"containerRoutes,omitempty"`
+	Settings        *Settings         `json:"settings,omitempty"`
+	PciAddress      string            `json:"pciAddress,omitempty"`
+	IsDelete        bool              `json:"isDelete,omitempty"`
+	PodLabels       map[string]string `json:"podLabels,omitempty"`
+	VfSettings      *VfSettings       `json:"vfSettings,omitempty"`
+}
+
+func (m *AddRequest) GetNetns() string {
+	if m == nil {
+		return ""
+	}
+	return m.Netns
+}
+
+func (m *AddRequest) GetInterfaceName() string {
+	if m == nil {
+		return ""
+	}
+	return m.InterfaceName
+}
+
+func (m *AddRequest) GetContainerIps() []string {
+	if m == nil {
+		return nil
+	}
+	return m.ContainerIps
+}
+
+func (m *AddRequest) GetContainerRoutes() []*Route {
+	if m == nil {
+		return nil
+	}
+	return m.ContainerRoutes
+}
+
+func (m *AddRequest) GetSettings() *Settings {
+	if m == nil {
+		return nil
+	}
+	return m.Settings
+}
+
+func (m *AddRequest) GetPciAddress() string {
+	if m == nil {
+		return ""
+	}
+	return m.PciAddress
+}
+
+func (m *AddRequest) GetIsDelete() bool {
+	if m == nil {
+		return false
+	}
+	return m.IsDelete
+}
+
+func (m *AddRequest) GetPodLabels() map[string]string {
+	if m == nil {
+		return nil
+	}
+	return m.PodLabels
+}
+
+func (m *AddRequest) GetVfSettings() *VfSettings {
+	if m == nil {
+		return nil
+	}
+	return m.VfSettings
+}
+
+// Marshal renders m for the binary request/response log and for the
+// admission webhook's remote validator payload.
+func (m *AddRequest) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// DelRequest is the CNI DEL request sent to the infra-agent CNI server.
+type DelRequest struct {
+	Netns         string `json:"netns,omitempty"`
+	InterfaceName string `json:"interfaceName,omitempty"`
+	PciAddress    string `json:"pciAddress,omitempty"`
+}
+
+func (m *DelRequest) GetNetns() string {
+	if m == nil {
+		return ""
+	}
+	return m.Netns
+}
+
+func (m *DelRequest) GetInterfaceName() string {
+	if m == nil {
+		return ""
+	}
+	return m.InterfaceName
+}
+
+func (m *DelRequest) GetPciAddress() string {
+	if m == nil {
+		return ""
+	}
+	return m.PciAddress
+}
+
+func (m *DelRequest) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// AddResponse is the CNI server's reply to an AddRequest.
+type AddResponse struct {
+	Success bool   `json:"success,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (m *AddResponse) GetSuccess() bool {
+	if m == nil {
+		return false
+	}
+	return m.Success
+}
+
+func (m *AddResponse) GetError() string {
+	if m == nil {
+		return ""
+	}
+	return m.Error
+}
+
+func (m *AddResponse) Marshal() ([]byte, error) {
+	return json.Marshal(m)
+}